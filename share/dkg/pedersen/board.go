@@ -0,0 +1,122 @@
+package dkg
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+
+	"github.com/DOSNetwork/core/share"
+)
+
+// Phase identifies a step of the deal/response/justification round trip
+// driven by Protocol.
+type Phase int
+
+// The three phases of the Pedersen DKG round trip, in order.
+const (
+	DealPhase Phase = iota
+	ResponsePhase
+	JustificationPhase
+)
+
+// Bundle batches every message a single participant sends during one
+// phase into one authenticated envelope, instead of one message per peer
+// pair. Exactly one of Deals, Resps or Justifs is populated,
+// matching Phase.
+type Bundle struct {
+	// Issuer is the index of the node that produced this bundle: its
+	// dealer-space index (old committee, when resharing) for deal and
+	// justification bundles, and its receiver-space index (new
+	// committee, when resharing) for response bundles.
+	Issuer share.Index
+	Phase  Phase
+
+	// Session identifies the run of the protocol this bundle belongs
+	// to; Protocol rejects any incoming bundle whose Session doesn't
+	// match its own, so a bundle can't be replayed into a different
+	// round even though it carries a valid signature.
+	Session []byte
+
+	Deals   []*Deal
+	Resps   []*Response
+	Justifs []*Justification
+
+	// Signature authenticates Issuer/Phase/Session and the content of
+	// every message carried in Deals/Resps/Justifs under the issuer's
+	// longterm Ed25519 key, so a Board implementation backed by an
+	// unauthenticated transport (plain gossip, a public queue, ...)
+	// still lets every recipient both reject bundles that don't
+	// originate from the participant they claim to be, and detect a
+	// relay that swapped in different message content for a bundle it
+	// otherwise left alone.
+	Signature []byte
+}
+
+// signedPayload returns the bytes that Signature is computed over: the
+// bundle's metadata (issuer, phase, session) together with a transcript
+// of every message it carries, so the signature binds to the actual
+// deals/responses/justifications being sent, not just their count.
+func (b *Bundle) signedPayload() []byte {
+	buf := make([]byte, 0, 64)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(b.Issuer))
+	buf = append(buf, byte(b.Phase))
+	buf = appendLenPrefixed(buf, b.Session)
+
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(b.Deals)))
+	for _, dd := range b.Deals {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(dd.Index))
+		buf = appendLenPrefixed(buf, dd.Deal.DHKey)
+		buf = appendLenPrefixed(buf, dd.Deal.Signature)
+		buf = appendLenPrefixed(buf, dd.Deal.Nonce)
+		buf = appendLenPrefixed(buf, dd.Deal.Cipher)
+	}
+
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(b.Resps)))
+	for _, r := range b.Resps {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(r.Index))
+		buf = appendLenPrefixed(buf, r.Response.SessionID)
+		buf = binary.BigEndian.AppendUint32(buf, r.Response.Index)
+		buf = append(buf, byte(r.Response.Status))
+		buf = appendLenPrefixed(buf, r.Response.Signature)
+	}
+
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(b.Justifs)))
+	for _, j := range b.Justifs {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(j.Index))
+		buf = appendLenPrefixed(buf, j.Justification.SessionID)
+		buf = binary.BigEndian.AppendUint32(buf, j.Justification.Index)
+		buf = appendLenPrefixed(buf, j.Justification.Signature)
+	}
+
+	return buf
+}
+
+// appendLenPrefixed appends v to buf preceded by its length, so that two
+// adjacent variable-length fields can't be confused for each other (e.g.
+// a 1-byte field followed by a 2-byte one vs. a 3-byte one followed by
+// nothing).
+func appendLenPrefixed(buf, v []byte) []byte {
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(v)))
+	return append(buf, v...)
+}
+
+// sign authenticates b under priv, the issuer's longterm Ed25519 key.
+func (b *Bundle) sign(priv ed25519.PrivateKey) {
+	b.Signature = ed25519.Sign(priv, b.signedPayload())
+}
+
+// verify checks that b was authenticated under pub, the claimed issuer's
+// longterm Ed25519 public key.
+func (b *Bundle) verify(pub ed25519.PublicKey) bool {
+	return ed25519.Verify(pub, b.signedPayload(), b.Signature)
+}
+
+// Board is the transport abstraction Protocol drives the DKG round trip
+// over: it only needs to move Bundles around, so it can be backed by
+// whatever gossip layer, RPC mesh or message queue the embedding
+// application already uses.
+type Board interface {
+	// Broadcast sends b to every other participant.
+	Broadcast(b *Bundle) error
+	// Incoming delivers bundles received from other participants.
+	Incoming() <-chan *Bundle
+}
@@ -4,6 +4,7 @@ package dkg
 
 import (
 	"errors"
+	"sort"
 
 	"github.com/DOSNetwork/core/share"
 	vss "github.com/DOSNetwork/core/share/vss/pedersen"
@@ -47,7 +48,7 @@ func (d *DistKeyShare) Commitments() []kyber.Point {
 // the Dealer in question.
 type Justification struct {
 	// Index of the Dealer who answered with this Justification
-	Index uint32
+	Index share.Index
 	// Justification issued from the Dealer
 	Justification *vss.Justification
 }
@@ -56,7 +57,7 @@ type Justification struct {
 type DistKeyGenerator struct {
 	suite Suite
 
-	index uint32
+	index share.Index
 	long  kyber.Scalar
 	pub   kyber.Point
 
@@ -65,7 +66,35 @@ type DistKeyGenerator struct {
 	t int
 
 	dealer    *vss.Dealer
-	verifiers map[uint32]*vss.Verifier
+	verifiers map[share.Index]*vss.Verifier
+
+	// The following fields are only set when this DistKeyGenerator was
+	// created via NewDistKeyHandler to reshare a secret from an old
+	// committee to a (possibly disjoint) new committee. See reshare.go.
+
+	// oldThreshold is the number of old dealers that must certify their
+	// deal before resharing can complete; zero for a plain DKG.
+	oldThreshold int
+	// oldPublic is the previously certified public polynomial of the old
+	// group. Incoming deals are checked against it: the dealer at old
+	// index i must commit to a polynomial whose constant term is the
+	// old share oldPublic.Eval(i).
+	oldPublic *share.PubPoly
+	// oldNodes and newNodes hold, respectively, the old and new committee
+	// public keys. A node that is only in the old set deals but never
+	// receives; a node only in the new set receives but never deals.
+	oldNodes []kyber.Point
+	newNodes []kyber.Point
+	// canIssue is true if this node is a member of the old committee and
+	// therefore deals out (a share of) its old secret.
+	canIssue bool
+	// canReceive is true if this node is a member of the new committee
+	// and therefore expects to end up with a new share.
+	canReceive bool
+	// oldIndex and newIndex are this node's index in oldNodes/newNodes,
+	// meaningful only when canIssue/canReceive is true respectively.
+	oldIndex share.Index
+	newIndex share.Index
 }
 
 // initDistKeyGenerator returns a dist key generator with the given secret as
@@ -74,11 +103,11 @@ func initDistKeyGenerator(suite Suite, longterm kyber.Scalar, participants []kyb
 	pub := suite.Point().Mul(longterm, nil)
 	// find our index
 	var found bool
-	var index uint32
+	var index share.Index
 	for i, p := range participants {
 		if p.Equal(pub) {
 			found = true
-			index = uint32(i)
+			index = share.Index(i)
 			break
 		}
 	}
@@ -95,13 +124,16 @@ func initDistKeyGenerator(suite Suite, longterm kyber.Scalar, participants []kyb
 
 	return &DistKeyGenerator{
 		dealer:       dealer,
-		verifiers:    make(map[uint32]*vss.Verifier),
+		verifiers:    make(map[share.Index]*vss.Verifier),
 		t:            t,
 		suite:        suite,
 		long:         longterm,
 		pub:          pub,
 		participants: participants,
 		index:        index,
+		canIssue:     true,
+		canReceive:   true,
+		newIndex:     index,
 	}, nil
 }
 
@@ -136,18 +168,31 @@ func NewDistKeyGeneratorWithoutSecret(suite Suite, longterm kyber.Scalar, partic
 // sever problem with the configuration or implementation and
 // results in a panic.
 func (d *DistKeyGenerator) Deals() (map[int]*Deal, error) {
+	if !d.canIssue {
+		return nil, errors.New("dkg: only dealers can produce deals")
+	}
 	deals, err := d.dealer.EncryptedDeals()
 	if err != nil {
 		return nil, err
 	}
+	// dealIndex identifies this dealer to the recipients: its own index in
+	// the old committee when resharing, or simply its index otherwise.
+	dealIndex := d.index
+	if d.oldPublic != nil {
+		dealIndex = d.oldIndex
+	}
 	dd := make(map[int]*Deal)
-	for i := range d.participants {
+	recipients := d.participants
+	if d.oldPublic != nil {
+		recipients = d.newNodes
+	}
+	for i := range recipients {
 		distd := &Deal{
-			Index: d.index,
+			Index: dealIndex,
 			Deal:  deals[i],
 		}
-		if i == int(d.index) {
-			if _, ok := d.verifiers[d.index]; ok {
+		if d.canReceive && i == int(d.newIndex) {
+			if _, ok := d.verifiers[dealIndex]; ok {
 				// already processed our own deal
 				continue
 			}
@@ -168,8 +213,21 @@ func (d *DistKeyGenerator) Deals() (map[int]*Deal, error) {
 // error in case the deal has already been stored, or if the deal is incorrect
 // (see vss.Verifier.ProcessEncryptedDeal).
 func (d *DistKeyGenerator) ProcessDeal(dd *Deal) (*Response, error) {
+	if !d.canReceive {
+		return nil, errors.New("dkg: only receivers can process deals")
+	}
+
+	// dealers is the list a dealer index is resolved against: the old
+	// committee when resharing, otherwise the (single) participant list.
+	dealers := d.participants
+	receivers := d.participants
+	if d.oldPublic != nil {
+		dealers = d.oldNodes
+		receivers = d.newNodes
+	}
+
 	// public key of the dealer
-	pub, ok := findPub(d.participants, dd.Index)
+	pub, ok := findPub(dealers, dd.Index)
 	if !ok {
 		return nil, errors.New("dkg: dist deal out of bounds index")
 	}
@@ -179,7 +237,7 @@ func (d *DistKeyGenerator) ProcessDeal(dd *Deal) (*Response, error) {
 	}
 
 	// verifier receiving the dealer's deal
-	ver, err := vss.NewVerifier(d.suite, d.long, pub, d.participants)
+	ver, err := vss.NewVerifier(d.suite, d.long, pub, receivers)
 	if err != nil {
 		return nil, err
 	}
@@ -190,6 +248,18 @@ func (d *DistKeyGenerator) ProcessDeal(dd *Deal) (*Response, error) {
 		return nil, err
 	}
 
+	// When resharing to a new committee, a deal is only acceptable if its
+	// constant term matches the dealer's previously certified old share, as
+	// given by the old group's public polynomial evaluated at the dealer's
+	// old index. This is what ties the freshly dealt sub-shares back to the
+	// secret that was actually certified by the old committee.
+	if d.oldPublic != nil {
+		expected := d.oldPublic.Eval(int(dd.Index)).V
+		if !expected.Equal(ver.Deal().Commitments[0]) {
+			return nil, errors.New("dkg: deal does not match old committee's public polynomial")
+		}
+	}
+
 	// Set StatusApproval for the verifier that represents the participant
 	// that distibuted the Deal
 	d.verifiers[dd.Index].UnsafeSetResponseDKG(dd.Index, vss.StatusApproval)
@@ -215,7 +285,11 @@ func (d *DistKeyGenerator) ProcessResponse(resp *Response) (*Justification, erro
 		return nil, err
 	}
 
-	if resp.Index != uint32(d.index) {
+	dealerSelfIndex := d.index
+	if d.oldPublic != nil {
+		dealerSelfIndex = d.oldIndex
+	}
+	if !d.canIssue || resp.Index != dealerSelfIndex {
 		return nil, nil
 	}
 
@@ -232,7 +306,7 @@ func (d *DistKeyGenerator) ProcessResponse(resp *Response) (*Justification, erro
 	}
 
 	return &Justification{
-		Index:         d.index,
+		Index:         dealerSelfIndex,
 		Justification: j,
 	}, nil
 }
@@ -255,10 +329,15 @@ func (d *DistKeyGenerator) SetTimeout() {
 	}
 }
 
-// Certified returns true if at least t deals are certified (see
+// Certified returns true if enough deals are certified (see
 // vss.Verifier.DealCertified()). If the distribution is certified, the protocol
-// can continue using d.SecretCommits().
+// can continue using d.DistKeyShare(). When resharing to a new committee, the
+// required count is OldThreshold certified old dealers rather than every
+// participant.
 func (d *DistKeyGenerator) Certified() bool {
+	if d.oldThreshold > 0 {
+		return len(d.QUAL()) >= d.oldThreshold
+	}
 	return len(d.QUAL()) >= len(d.participants)
 }
 
@@ -269,16 +348,16 @@ func (d *DistKeyGenerator) Certified() bool {
 // the distributed public key with SecretCommits() and ProcessSecretCommits().
 func (d *DistKeyGenerator) QUAL() []int {
 	var good []int
-	d.qualIter(func(i uint32, v *vss.Verifier) bool {
+	d.qualIter(func(i share.Index, v *vss.Verifier) bool {
 		good = append(good, int(i))
 		return true
 	})
 	return good
 }
 
-func (d *DistKeyGenerator) isInQUAL(idx uint32) bool {
+func (d *DistKeyGenerator) isInQUAL(idx share.Index) bool {
 	var found bool
-	d.qualIter(func(i uint32, v *vss.Verifier) bool {
+	d.qualIter(func(i share.Index, v *vss.Verifier) bool {
 		if i == idx {
 			found = true
 			return false
@@ -288,7 +367,7 @@ func (d *DistKeyGenerator) isInQUAL(idx uint32) bool {
 	return found
 }
 
-func (d *DistKeyGenerator) qualIter(fn func(idx uint32, v *vss.Verifier) bool) {
+func (d *DistKeyGenerator) qualIter(fn func(idx share.Index, v *vss.Verifier) bool) {
 	for i, v := range d.verifiers {
 		if v.DealCertified() {
 			if !fn(i, v) {
@@ -309,12 +388,15 @@ func (d *DistKeyGenerator) DistKeyShare() (*DistKeyShare, error) {
 	if !d.Certified() {
 		return nil, errors.New("dkg: distributed key not certified")
 	}
+	if d.oldPublic != nil {
+		return d.resharedDistKeyShare()
+	}
 
 	sh := d.suite.Scalar().Zero()
 	var pub *share.PubPoly
 	var err error
 
-	d.qualIter(func(i uint32, v *vss.Verifier) bool {
+	d.qualIter(func(i share.Index, v *vss.Verifier) bool {
 		// share of dist. secret = sum of all share received.
 		deal := v.Deal()
 		s := deal.SecShare.V
@@ -338,13 +420,106 @@ func (d *DistKeyGenerator) DistKeyShare() (*DistKeyShare, error) {
 	return &DistKeyShare{
 		Commits: commits,
 		Share: &share.PriShare{
-			I: int(d.index),
+			I: d.index,
 			V: sh,
 		},
 		PrivatePoly: d.dealer.PrivatePoly().Coefficients(),
 	}, nil
 }
 
+// resharedDistKeyShare computes the new share and the new group public
+// polynomial when this DistKeyGenerator was built to reshare from an old
+// committee to a new one. Unlike the plain DKG case, the new share is not a
+// plain sum of the received sub-shares: it is the Lagrange interpolation,
+// at x=0, of the sub-shares indexed by their dealer's *old* index, which is
+// exactly what reconstructs the polynomial that ties the new shares back to
+// the old, already certified, secret. The share and the public commitments
+// must be interpolated over the exact same canonical set of OldThreshold
+// dealer indices, or the two will silently drift apart whenever more than
+// OldThreshold dealers are qualified.
+func (d *DistKeyGenerator) resharedDistKeyShare() (*DistKeyShare, error) {
+	if !d.canReceive {
+		return nil, errors.New("dkg: only new committee members obtain a resulting share")
+	}
+
+	var verifiers []*vss.Verifier
+	var indices []share.Index
+	d.qualIter(func(j share.Index, v *vss.Verifier) bool {
+		indices = append(indices, j)
+		verifiers = append(verifiers, v)
+		return true
+	})
+
+	// Canonicalize on the OldThreshold lowest-indexed qualified dealers so
+	// every new-committee member interpolates over the same set, regardless
+	// of the non-deterministic order QUAL was discovered in.
+	order := make([]int, len(indices))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return indices[order[a]] < indices[order[b]] })
+	if len(order) > d.oldThreshold {
+		order = order[:d.oldThreshold]
+	}
+
+	canonical := make([]share.Index, len(order))
+	subShares := make([]*share.PriShare, len(order))
+	for k, o := range order {
+		canonical[k] = indices[o]
+		subShares[k] = &share.PriShare{I: indices[o], V: verifiers[o].Deal().SecShare.V}
+	}
+
+	newShare, err := share.RecoverSecret(d.suite, subShares, len(canonical), len(d.oldNodes))
+	if err != nil {
+		return nil, err
+	}
+
+	var pub *share.PubPoly
+	for k, o := range order {
+		w := lagrangeWeight(d.suite, canonical[k], canonical)
+		deal := verifiers[o].Deal()
+		scaled := make([]kyber.Point, len(deal.Commitments))
+		for c := range deal.Commitments {
+			scaled[c] = d.suite.Point().Mul(w, deal.Commitments[c])
+		}
+		poly := share.NewPubPoly(d.suite, nil, scaled)
+		if pub == nil {
+			pub = poly
+			continue
+		}
+		pub, err = pub.Add(poly)
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, commits := pub.Info()
+
+	return &DistKeyShare{
+		Commits: commits,
+		Share: &share.PriShare{
+			I: d.newIndex,
+			V: newShare,
+		},
+	}, nil
+}
+
+// lagrangeWeight returns the Lagrange coefficient L_j(0) for index j among
+// the given set of contributing old-committee indices.
+func lagrangeWeight(suite Suite, j share.Index, indices []share.Index) kyber.Scalar {
+	xj := suite.Scalar().SetInt64(1 + int64(j))
+	num := suite.Scalar().One()
+	den := suite.Scalar().One()
+	for _, m := range indices {
+		if m == j {
+			continue
+		}
+		xm := suite.Scalar().SetInt64(1 + int64(m))
+		num = suite.Scalar().Mul(num, suite.Scalar().Neg(xm))
+		den = suite.Scalar().Mul(den, suite.Scalar().Sub(xj, xm))
+	}
+	return suite.Scalar().Div(num, den)
+}
+
 //Renew adds the new distributed key share g (with secret 0) to the distributed key share d.
 func (d *DistKeyShare) Renew(suite Suite, g *DistKeyShare) (*DistKeyShare, error) {
 	//Check G(0) = 0*G.
@@ -371,8 +546,8 @@ func (d *DistKeyShare) Renew(suite Suite, g *DistKeyShare) (*DistKeyShare, error
 	}, nil
 }
 
-func findPub(list []kyber.Point, i uint32) (kyber.Point, bool) {
-	if i >= uint32(len(list)) {
+func findPub(list []kyber.Point, i share.Index) (kyber.Point, bool) {
+	if i >= share.Index(len(list)) {
 		return nil, false
 	}
 	return list[i], true
@@ -0,0 +1,332 @@
+package dkg
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"time"
+
+	"github.com/DOSNetwork/core/share"
+	"github.com/dedis/kyber"
+)
+
+// Protocol drives a full deal/response/justification round trip of a
+// DistKeyGenerator over a Board, so that callers don't have to wire up
+// the message routing and phase bookkeeping themselves. Each phase
+// bundles every message this node has to send during that phase into one
+// signed Bundle and waits for either a quorum of the other participants'
+// bundles (see dealQuorum and responseQuorum: every other participant for
+// a plain DKG, a super-majority when resharing; the justification phase
+// instead waits until the distribution is certified) or its own phase
+// timeout before moving on.
+type Protocol struct {
+	dkg   *DistKeyGenerator
+	board Board
+
+	priv ed25519.PrivateKey
+	// dealerSigs and receiverSigs hold the longterm Ed25519 public key of
+	// every participant in, respectively, the dealer and receiver index
+	// spaces (see DistKeyGenerator.dealerSpace/receiverSpace): the same
+	// list for a plain DKG, and the old/new committee's keys
+	// respectively when resharing.
+	dealerSigs   []ed25519.PublicKey
+	receiverSigs []ed25519.PublicKey
+
+	// session identifies this particular run of the protocol: it is
+	// carried in and signed with every Bundle, and verifyBundle rejects
+	// any incoming bundle whose Session doesn't match, so a bundle
+	// authenticated for one DKG round can't be replayed into another
+	// one even by a node whose longterm key and index are unchanged.
+	// Callers must agree on the same session out of band (e.g. derive
+	// it from a round number or epoch) before starting the round.
+	session []byte
+
+	phaseTimeout time.Duration
+}
+
+// NewProtocol returns a Protocol that drives d to completion over board,
+// authenticating its own bundles under priv and verifying incoming ones
+// against dealerSigs/receiverSigs, which must be parallel to d's dealer
+// and receiver index spaces (for a plain, non-resharing DKG, both are
+// simply parallel to participants). session identifies this run of the
+// protocol to every participant and must be the same value for everyone
+// taking part, and unique to this round (e.g. a per-round nonce or
+// monotonic round number), or cross-run bundle replay becomes possible.
+func NewProtocol(d *DistKeyGenerator, board Board, priv ed25519.PrivateKey, dealerSigs, receiverSigs []ed25519.PublicKey, session []byte) *Protocol {
+	return &Protocol{
+		dkg:          d,
+		board:        board,
+		priv:         priv,
+		dealerSigs:   dealerSigs,
+		receiverSigs: receiverSigs,
+		session:      session,
+	}
+}
+
+// SetTimeout sets how long each phase waits for its quorum of bundles
+// before it gives up and moves on with whatever was collected.
+// A zero timeout (the default) makes every phase wait indefinitely,
+// relying solely on ctx to bound Run.
+func (p *Protocol) SetTimeout(d time.Duration) {
+	p.phaseTimeout = d
+}
+
+// Run drives the DKG to completion: it broadcasts this node's deals,
+// collects and processes incoming ones, then does the same for responses
+// and justifications, and finally returns the resulting DistKeyShare.
+func (p *Protocol) Run(ctx context.Context) (*DistKeyShare, error) {
+	d := p.dkg
+
+	ownResponses, err := p.runDeals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ownJustifs, err := p.runResponses(ctx, ownResponses)
+	if err != nil {
+		return nil, err
+	}
+
+	// SetTimeout finalizes every verifier still waiting for a response as
+	// complaining, so the justification phase and the final Certified/
+	// DistKeyShare calls below see a definitive QUAL instead of blocking
+	// forever on a straggler that never responds.
+	d.SetTimeout()
+
+	if err := p.runJustifications(ctx, ownJustifs); err != nil {
+		return nil, err
+	}
+
+	return d.DistKeyShare()
+}
+
+// runDeals broadcasts this node's deals, if it is a dealer, then collects
+// and processes incoming deal bundles from dealQuorum members of the
+// dealer space (or until the phase times out), returning the responses
+// this node produced while doing so.
+func (p *Protocol) runDeals(ctx context.Context) ([]*Response, error) {
+	d := p.dkg
+
+	if d.canIssue {
+		deals, err := d.Deals()
+		if err != nil {
+			return nil, err
+		}
+		bundle := &Bundle{Issuer: d.dealerIndex(), Phase: DealPhase, Session: p.session}
+		for _, dd := range deals {
+			bundle.Deals = append(bundle.Deals, dd)
+		}
+		bundle.sign(p.priv)
+		if err := p.board.Broadcast(bundle); err != nil {
+			return nil, err
+		}
+	}
+
+	if !d.canReceive {
+		return nil, nil
+	}
+
+	quorum := dealQuorum(d)
+	seen := make(map[share.Index]bool)
+	var responses []*Response
+	timeout := p.timeoutAfter()
+	for len(seen) < quorum {
+		select {
+		case <-ctx.Done():
+			return responses, ctx.Err()
+		case <-timeout:
+			return responses, nil
+		case b := <-p.board.Incoming():
+			if b.Phase != DealPhase || seen[b.Issuer] || !p.verifyBundle(b, true) {
+				continue
+			}
+			seen[b.Issuer] = true
+			for _, dd := range b.Deals {
+				// A deal not addressed to this node fails to process
+				// (it was encrypted for someone else); that is expected
+				// since a dealer's bundle carries every recipient's deal
+				// at once, so every node can learn it over one Broadcast.
+				// ProcessDeal registers the dealer's verifier before it
+				// decrypts, so a failed attempt's verifier is dropped
+				// again; otherwise it would make ProcessDeal reject this
+				// node's own deal from the same dealer as a duplicate.
+				_, had := d.verifiers[dd.Index]
+				resp, err := d.ProcessDeal(dd)
+				if err != nil {
+					if !had {
+						delete(d.verifiers, dd.Index)
+					}
+					continue
+				}
+				responses = append(responses, resp)
+			}
+		}
+	}
+	return responses, nil
+}
+
+// runResponses broadcasts own, the responses produced while processing
+// deals, then collects and processes incoming response bundles from
+// responseQuorum members of the receiver space (or until the phase times
+// out), returning the justifications this node produced while doing so.
+func (p *Protocol) runResponses(ctx context.Context, own []*Response) ([]*Justification, error) {
+	d := p.dkg
+
+	if len(own) > 0 {
+		bundle := &Bundle{Issuer: d.newIndex, Phase: ResponsePhase, Resps: own, Session: p.session}
+		bundle.sign(p.priv)
+		if err := p.board.Broadcast(bundle); err != nil {
+			return nil, err
+		}
+	}
+
+	quorum := responseQuorum(d)
+	seen := make(map[share.Index]bool)
+	var justifs []*Justification
+	timeout := p.timeoutAfter()
+	for len(seen) < quorum {
+		select {
+		case <-ctx.Done():
+			return justifs, ctx.Err()
+		case <-timeout:
+			return justifs, nil
+		case b := <-p.board.Incoming():
+			if b.Phase != ResponsePhase || seen[b.Issuer] || !p.verifyBundle(b, false) {
+				continue
+			}
+			seen[b.Issuer] = true
+			for _, r := range b.Resps {
+				j, err := d.ProcessResponse(r)
+				if err != nil || j == nil {
+					continue
+				}
+				justifs = append(justifs, j)
+			}
+		}
+	}
+	return justifs, nil
+}
+
+// runJustifications broadcasts own, if any, then waits until either this
+// node's distribution is certified or the phase times out, processing
+// every justification bundle it receives in the meantime.
+func (p *Protocol) runJustifications(ctx context.Context, own []*Justification) error {
+	d := p.dkg
+
+	if len(own) > 0 {
+		bundle := &Bundle{Issuer: d.dealerIndex(), Phase: JustificationPhase, Justifs: own, Session: p.session}
+		bundle.sign(p.priv)
+		if err := p.board.Broadcast(bundle); err != nil {
+			return err
+		}
+	}
+
+	timeout := p.timeoutAfter()
+	for !d.Certified() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			return nil
+		case b := <-p.board.Incoming():
+			if b.Phase != JustificationPhase || !p.verifyBundle(b, true) {
+				continue
+			}
+			for _, j := range b.Justifs {
+				_ = d.ProcessJustification(j)
+			}
+		}
+	}
+	return nil
+}
+
+// timeoutAfter returns a channel that fires once the current phase
+// timeout elapses, or nil (which blocks forever in a select) if no
+// timeout was configured via SetTimeout.
+func (p *Protocol) timeoutAfter() <-chan time.Time {
+	if p.phaseTimeout <= 0 {
+		return nil
+	}
+	return time.After(p.phaseTimeout)
+}
+
+// verifyBundle authenticates b under the longterm Ed25519 key its
+// claimed Issuer has in the dealer space (asDealer) or the receiver
+// space (!asDealer), and rejects it outright if it was not produced for
+// this run of the protocol.
+func (p *Protocol) verifyBundle(b *Bundle, asDealer bool) bool {
+	if !bytes.Equal(b.Session, p.session) {
+		return false
+	}
+	sigs := p.receiverSigs
+	if asDealer {
+		sigs = p.dealerSigs
+	}
+	if int(b.Issuer) >= len(sigs) {
+		return false
+	}
+	return b.verify(sigs[b.Issuer])
+}
+
+// superMajority returns the smallest count that is more than two thirds
+// of n.
+func superMajority(n int) int {
+	return (2*n)/3 + 1
+}
+
+// dealQuorum returns how many deal bundles runDeals waits for before
+// moving on. Resharing only ever needs OldThreshold certified old
+// dealers (see DistKeyGenerator.Certified), so a super-majority of the
+// dealer space is already enough there. A plain DKG's Certified demands
+// every participant, so runDeals must not give up early and discard
+// whatever deal bundles are still arriving; it waits for n-1 rather than
+// n, since a node's own Broadcast never comes back to it over Incoming,
+// so seen can never include this node's own bundle.
+func dealQuorum(d *DistKeyGenerator) int {
+	n := len(d.dealerSpace())
+	if d.oldPublic != nil {
+		return superMajority(n)
+	}
+	return n - 1
+}
+
+// responseQuorum returns how many response bundles runResponses waits
+// for before moving on, mirroring dealQuorum: a plain DKG needs every
+// other receiver's responses processed for every dealer to reach the
+// full QUAL Certified requires (again n-1, not n, since this node never
+// sees its own broadcast via Incoming), while resharing only needs a
+// super-majority.
+func responseQuorum(d *DistKeyGenerator) int {
+	n := len(d.receiverSpace())
+	if d.oldPublic != nil {
+		return superMajority(n)
+	}
+	return n - 1
+}
+
+// dealerSpace returns the list of public keys a dealer index is resolved
+// against: the old committee when resharing, otherwise the participants.
+func (d *DistKeyGenerator) dealerSpace() []kyber.Point {
+	if d.oldPublic != nil {
+		return d.oldNodes
+	}
+	return d.participants
+}
+
+// receiverSpace returns the list of public keys a receiver index is
+// resolved against: the new committee when resharing, otherwise the
+// participants.
+func (d *DistKeyGenerator) receiverSpace() []kyber.Point {
+	if d.oldPublic != nil {
+		return d.newNodes
+	}
+	return d.participants
+}
+
+// dealerIndex returns this node's own index in the dealer space.
+func (d *DistKeyGenerator) dealerIndex() share.Index {
+	if d.oldPublic != nil {
+		return d.oldIndex
+	}
+	return d.index
+}
@@ -0,0 +1,215 @@
+package dkg
+
+import (
+	"context"
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"github.com/DOSNetwork/core/share"
+)
+
+// memoryBoard is an in-memory Board that fans a Broadcast out to every
+// other node sharing the same memoryNetwork, optionally dropping
+// messages according to drop. It exists purely for tests: a real
+// deployment backs Board with its own gossip/RPC transport.
+type memoryBoard struct {
+	self share.Index
+	net  *memoryNetwork
+	in   chan *Bundle
+}
+
+func (b *memoryBoard) Broadcast(bundle *Bundle) error {
+	b.net.deliver(b.self, bundle)
+	return nil
+}
+
+func (b *memoryBoard) Incoming() <-chan *Bundle {
+	return b.in
+}
+
+// memoryNetwork wires a set of memoryBoards together and lets tests drop
+// messages selectively to simulate failures, partitions and Byzantine
+// senders that broadcast to only part of the network.
+type memoryNetwork struct {
+	boards []*memoryBoard
+	// drop, if set, is consulted for every message; returning true
+	// discards it before it reaches to.
+	drop func(from, to share.Index) bool
+}
+
+func newMemoryNetwork(n int) *memoryNetwork {
+	net := &memoryNetwork{}
+	net.boards = make([]*memoryBoard, n)
+	for i := range net.boards {
+		net.boards[i] = &memoryBoard{
+			self: share.Index(i),
+			net:  net,
+			in:   make(chan *Bundle, 16*n),
+		}
+	}
+	return net
+}
+
+func (net *memoryNetwork) deliver(from share.Index, bundle *Bundle) {
+	for _, to := range net.boards {
+		if to.self == from {
+			continue
+		}
+		if net.drop != nil && net.drop(from, to.self) {
+			continue
+		}
+		to.in <- bundle
+	}
+}
+
+func runProtocolDKG(test *testing.T, dkgs []*DistKeyGenerator, privs []ed25519.PrivateKey, pubs []ed25519.PublicKey, net *memoryNetwork) []*DistKeyShare {
+	test.Helper()
+
+	session := []byte("test-session")
+
+	shares := make([]*DistKeyShare, len(dkgs))
+	errs := make(chan error, len(dkgs))
+	for i := range dkgs {
+		i := i
+		go func() {
+			proto := NewProtocol(dkgs[i], net.boards[i], privs[i], pubs, pubs, session)
+			proto.SetTimeout(2 * time.Second)
+			s, err := proto.Run(context.Background())
+			shares[i] = s
+			errs <- err
+		}()
+	}
+	for range dkgs {
+		if err := <-errs; err != nil {
+			test.Fatal(err)
+		}
+	}
+	return shares
+}
+
+func TestProtocolFullCommittee(test *testing.T) {
+	n := 7
+	th := n/2 + 1
+	privs, pubs := genKeys(n)
+	sigPrivs, sigPubs := genSigKeys(n)
+
+	dkgs := make([]*DistKeyGenerator, n)
+	for i := range dkgs {
+		d, err := NewDistKeyGenerator(reshareSuite, privs[i], pubs, th)
+		if err != nil {
+			test.Fatal(err)
+		}
+		dkgs[i] = d
+	}
+
+	net := newMemoryNetwork(n)
+	shares := runProtocolDKG(test, dkgs, sigPrivs, sigPubs, net)
+
+	for i := 1; i < n; i++ {
+		if !shares[i].Public().Equal(shares[0].Public()) {
+			test.Fatalf("node %d disagrees on the group public key", i)
+		}
+	}
+}
+
+// reshareDKGs drives a plain DKG among n nodes and returns a fresh
+// DistKeyGenerator for each of them, configured to reshare the resulting
+// secret to the very same committee with the given old threshold. Unlike
+// a plain DKG, whose Certified() demands every single participant,
+// resharing only demands oldT certified old dealers, so it is the path
+// that actually exercises dropped-message and Byzantine-dealer tolerance.
+func reshareDKGs(test *testing.T, n, oldT int) []*DistKeyGenerator {
+	test.Helper()
+
+	privs, pubs := genKeys(n)
+	oldShares := runDKG(test, privs, pubs, oldT)
+
+	dkgs := make([]*DistKeyGenerator, n)
+	for i := range dkgs {
+		cfg := &Config{
+			Suite:        reshareSuite,
+			Longterm:     privs[i],
+			OldNodes:     pubs,
+			NewNodes:     pubs,
+			OldThreshold: oldT,
+			NewThreshold: oldT,
+			PublicCoeffs: oldShares[0].Commits,
+			Share:        oldShares[i],
+		}
+		d, err := NewDistKeyHandler(cfg)
+		if err != nil {
+			test.Fatal(err)
+		}
+		dkgs[i] = d
+	}
+	return dkgs
+}
+
+func TestProtocolDroppedMessages(test *testing.T) {
+	n := 7
+	oldT := n/2 + 1
+	dkgs := reshareDKGs(test, n, oldT)
+	sigPrivs, sigPubs := genSigKeys(n)
+
+	net := newMemoryNetwork(n)
+	// No bundle of node 0 (deals, responses or justifications) ever
+	// reaches node 1; resharing only requires oldT certified old dealers,
+	// so the round still completes for everyone without them.
+	net.drop = func(from, to share.Index) bool {
+		return from == 0 && to == 1
+	}
+
+	shares := runProtocolDKG(test, dkgs, sigPrivs, sigPubs, net)
+	for i := 1; i < n; i++ {
+		if !shares[i].Public().Equal(shares[0].Public()) {
+			test.Fatalf("node %d disagrees on the group public key", i)
+		}
+	}
+}
+
+func TestProtocolByzantineDealer(test *testing.T) {
+	n := 7
+	oldT := n/2 + 1
+	dkgs := reshareDKGs(test, n, oldT)
+	sigPrivs, sigPubs := genSigKeys(n)
+
+	// Swap in an unrelated public key for node 2: every bundle it signs
+	// and broadcasts under its real longterm key now fails verification
+	// against what the rest of the network has on file for it, so every
+	// honest node must reject it rather than let it poison their
+	// verifier state. Because resharing only needs oldT of the n old
+	// dealers, the round still completes without node 2's contribution.
+	forgedPub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		test.Fatal(err)
+	}
+	sigPubs[2] = forgedPub
+
+	net := newMemoryNetwork(n)
+	shares := runProtocolDKG(test, dkgs, sigPrivs, sigPubs, net)
+	for i := 1; i < n; i++ {
+		if i == 2 {
+			continue
+		}
+		if !shares[i].Public().Equal(shares[0].Public()) {
+			test.Fatalf("node %d disagrees on the group public key", i)
+		}
+	}
+}
+
+// genSigKeys returns n Ed25519 longterm signing keys used to authenticate
+// bundles, independent of the participants' DKG group keys.
+func genSigKeys(n int) ([]ed25519.PrivateKey, []ed25519.PublicKey) {
+	privs := make([]ed25519.PrivateKey, n)
+	pubs := make([]ed25519.PublicKey, n)
+	for i := 0; i < n; i++ {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			panic(err)
+		}
+		privs[i] = priv
+		pubs[i] = pub
+	}
+	return privs, pubs
+}
@@ -0,0 +1,96 @@
+package dkg
+
+import (
+	"errors"
+
+	"github.com/DOSNetwork/core/share"
+	vss "github.com/DOSNetwork/core/share/vss/pedersen"
+	"github.com/dedis/kyber"
+)
+
+// Config gathers the parameters needed to create a DistKeyGenerator, either
+// for a plain DKG or to reshare an already certified secret from an old
+// committee to a new, possibly disjoint, one.
+//
+// For a plain DKG, only Suite, Longterm, NewNodes and NewThreshold need to
+// be set; OldNodes is left empty.
+//
+// For a resharing, OldNodes/OldThreshold/PublicCoeffs describe the already
+// certified old group: PublicCoeffs are the coefficients of the old group's
+// public polynomial (as returned by DistKeyShare.Commits), and Share is
+// this node's own old share, required for any node that is a member of
+// OldNodes. A node that is not part of OldNodes runs as a pure receiver; a
+// node that is not part of NewNodes runs as a pure dealer and never calls
+// DistKeyShare.
+type Config struct {
+	Suite    Suite
+	Longterm kyber.Scalar
+
+	OldNodes     []kyber.Point
+	NewNodes     []kyber.Point
+	OldThreshold int
+	NewThreshold int
+	PublicCoeffs []kyber.Point
+	Share        *DistKeyShare
+}
+
+// NewDistKeyHandler returns a DistKeyGenerator configured according to c. If
+// c.OldNodes is empty this behaves like NewDistKeyGenerator; otherwise it
+// sets up a resharing from OldNodes to NewNodes.
+func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
+	if len(c.OldNodes) == 0 {
+		return NewDistKeyGenerator(c.Suite, c.Longterm, c.NewNodes, c.NewThreshold)
+	}
+	return newReshareDistKeyGenerator(c)
+}
+
+func newReshareDistKeyGenerator(c *Config) (*DistKeyGenerator, error) {
+	suite := c.Suite
+	pub := suite.Point().Mul(c.Longterm, nil)
+
+	oldIndex, canIssue := findIndex(c.OldNodes, pub)
+	newIndex, canReceive := findIndex(c.NewNodes, pub)
+	if !canIssue && !canReceive {
+		return nil, errors.New("dkg: public key not found in either the old or the new node list")
+	}
+
+	d := &DistKeyGenerator{
+		suite:        suite,
+		long:         c.Longterm,
+		pub:          pub,
+		participants: c.NewNodes,
+		t:            c.NewThreshold,
+		verifiers:    make(map[share.Index]*vss.Verifier),
+		oldThreshold: c.OldThreshold,
+		oldPublic:    share.NewPubPoly(suite, suite.Point().Base(), c.PublicCoeffs),
+		oldNodes:     c.OldNodes,
+		newNodes:     c.NewNodes,
+		canIssue:     canIssue,
+		canReceive:   canReceive,
+		oldIndex:     oldIndex,
+		newIndex:     newIndex,
+		index:        newIndex,
+	}
+
+	if canIssue {
+		if c.Share == nil {
+			return nil, errors.New("dkg: a node of the old committee must provide its old share to reshare")
+		}
+		dealer, err := vss.NewDealer(suite, c.Longterm, c.Share.Share.V, c.NewNodes, c.NewThreshold)
+		if err != nil {
+			return nil, err
+		}
+		d.dealer = dealer
+	}
+
+	return d, nil
+}
+
+func findIndex(list []kyber.Point, pub kyber.Point) (share.Index, bool) {
+	for i, p := range list {
+		if p.Equal(pub) {
+			return share.Index(i), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,168 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/DOSNetwork/core/suites"
+	"github.com/dedis/kyber"
+)
+
+var reshareSuite = suites.MustFind("bn256")
+
+// genKeys returns n longterm secrets and their corresponding public keys.
+func genKeys(n int) ([]kyber.Scalar, []kyber.Point) {
+	privs := make([]kyber.Scalar, n)
+	pubs := make([]kyber.Point, n)
+	for i := 0; i < n; i++ {
+		privs[i] = reshareSuite.Scalar().Pick(reshareSuite.RandomStream())
+		pubs[i] = reshareSuite.Point().Mul(privs[i], nil)
+	}
+	return privs, pubs
+}
+
+// runDKG drives a full plain DKG for the given participants and returns the
+// resulting DistKeyShare for each of them.
+func runDKG(t *testing.T, privs []kyber.Scalar, pubs []kyber.Point, threshold int) []*DistKeyShare {
+	dkgs := make([]*DistKeyGenerator, len(privs))
+	for i := range dkgs {
+		d, err := NewDistKeyGenerator(reshareSuite, privs[i], pubs, threshold)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dkgs[i] = d
+	}
+
+	resps := make([]*Response, 0)
+	for i := range dkgs {
+		deals, err := dkgs[i].Deals()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j, d := range deals {
+			r, err := dkgs[j].ProcessDeal(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resps = append(resps, r)
+		}
+	}
+
+	for _, r := range resps {
+		for i := range dkgs {
+			if int(r.Index) == i {
+				continue
+			}
+			if _, err := dkgs[i].ProcessResponse(r); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	shares := make([]*DistKeyShare, len(dkgs))
+	for i := range dkgs {
+		s, err := dkgs[i].DistKeyShare()
+		if err != nil {
+			t.Fatal(err)
+		}
+		shares[i] = s
+	}
+	return shares
+}
+
+func TestResharingSameCommittee(test *testing.T) {
+	n := 5
+	oldT := n/2 + 1
+	privs, pubs := genKeys(n)
+	oldShares := runDKG(test, privs, pubs, oldT)
+
+	publicCoeffs := oldShares[0].Commits
+
+	newDkgs := make([]*DistKeyGenerator, n)
+	for i := range newDkgs {
+		cfg := &Config{
+			Suite:        reshareSuite,
+			Longterm:     privs[i],
+			OldNodes:     pubs,
+			NewNodes:     pubs,
+			OldThreshold: oldT,
+			NewThreshold: oldT,
+			PublicCoeffs: publicCoeffs,
+			Share:        oldShares[i],
+		}
+		d, err := NewDistKeyHandler(cfg)
+		if err != nil {
+			test.Fatal(err)
+		}
+		newDkgs[i] = d
+	}
+
+	resps := make([]*Response, 0)
+	for i := range newDkgs {
+		deals, err := newDkgs[i].Deals()
+		if err != nil {
+			test.Fatal(err)
+		}
+		for j, d := range deals {
+			r, err := newDkgs[j].ProcessDeal(d)
+			if err != nil {
+				test.Fatal(err)
+			}
+			resps = append(resps, r)
+		}
+	}
+
+	for _, r := range resps {
+		for i := range newDkgs {
+			if _, err := newDkgs[i].ProcessResponse(r); err != nil {
+				test.Fatal(err)
+			}
+		}
+	}
+
+	newShares := make([]*DistKeyShare, n)
+	for i := range newDkgs {
+		s, err := newDkgs[i].DistKeyShare()
+		if err != nil {
+			test.Fatal(err)
+		}
+		newShares[i] = s
+	}
+
+	for i := 1; i < n; i++ {
+		if !newShares[i].Public().Equal(newShares[0].Public()) {
+			test.Fatal("resharing changed the group public key")
+		}
+	}
+	if !newShares[0].Public().Equal(oldShares[0].Public()) {
+		test.Fatal("resharing did not preserve the group public key")
+	}
+}
+
+// TestLargeCommitteeIndex runs a full DKG over a 300+-node committee. Its
+// purpose is to exercise share.Index (a uint32) across the whole DKG/VSS
+// pipeline with participant counts and indices that no longer fit in a
+// single byte. As an ordinary Go test it demonstrates
+// that indices 0 to 306 round-trip correctly on whatever architecture
+// it's built for; it does not itself force a 32-bit int, since that is a
+// property of the build, not the source. `make test-386` builds and runs
+// this same test (and the rest of the suite) with GOARCH=386, which is
+// the actual, repeatable way to exercise the int/uint32 boundary this
+// test guards, rather than a claim about it.
+func TestLargeCommitteeIndex(test *testing.T) {
+	if testing.Short() {
+		test.Skip("skipping large committee DKG in -short mode")
+	}
+	n := 307
+	t := n/2 + 1
+	privs, pubs := genKeys(n)
+	shares := runDKG(test, privs, pubs, t)
+
+	for i := 1; i < n; i++ {
+		if !shares[i].Public().Equal(shares[0].Public()) {
+			test.Fatalf("node %d disagrees on the group public key", i)
+		}
+		if int(shares[i].Share.I) != i {
+			test.Fatalf("node %d has share index %d, want %d", i, shares[i].Share.I, i)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package share
+
+// Index identifies a participant's position in a secret sharing or DKG
+// session (dealer, shareholder, verifier, ...). It is a named type rather
+// than a bare int or uint32 so that every package along the DKG/VSS
+// pipeline (share, share/vss/pedersen, share/dkg/pedersen) agrees on a
+// single, explicitly sized representation: uint32 comfortably indexes
+// committees with hundreds of participants while staying a fixed 4 bytes
+// on the wire and on 32-bit builds, where a bare `int` is only 32 bits and
+// a careless int/uint32 conversion can silently truncate.
+type Index uint32
@@ -0,0 +1,461 @@
+// Package share implements Shamir secret sharing and polynomial commitments.
+// Shamir's scheme allows you to split a secret value into multiple parts, so
+// called shares, by evaluating a secret sharing polynomial at certain points.
+// The shared secret can only be reconstructed (via Lagrange interpolation)
+// if a threshold of the participants provide their shares. A polynomial
+// commitment scheme allows a committer to commit to a secret sharing
+// polynomial so that a verifier can check the claimed evaluations of the
+// committed polynomial.
+package share
+
+import (
+	"crypto/cipher"
+	"errors"
+	"strings"
+
+	"github.com/dedis/kyber"
+)
+
+// PriShare represents a private share.
+type PriShare struct {
+	I Index        // Index of the private share
+	V kyber.Scalar // Value of the private share
+}
+
+// PriPoly represents a secret sharing polynomial.
+type PriPoly struct {
+	g      kyber.Group    // Cryptographic group
+	coeffs []kyber.Scalar // Coefficients of the polynomial
+}
+
+// NewPriPoly creates a new secret sharing polynomial for the cryptographic
+// group g, the secret sharing threshold t, and the secret to be shared s.
+// If s is nil, a new s is chosen using the given randomness stream rand.
+func NewPriPoly(g kyber.Group, t int, s kyber.Scalar, rand cipher.Stream) *PriPoly {
+	coeffs := make([]kyber.Scalar, t)
+	coeffs[0] = s
+	if coeffs[0] == nil {
+		coeffs[0] = g.Scalar().Pick(rand)
+	}
+	for i := 1; i < t; i++ {
+		coeffs[i] = g.Scalar().Pick(rand)
+	}
+	return &PriPoly{g: g, coeffs: coeffs}
+}
+
+// CoefficientsToPriPoly returns a PriPoly based on the given coefficients.
+func CoefficientsToPriPoly(g kyber.Group, coeffs []kyber.Scalar) *PriPoly {
+	return &PriPoly{g: g, coeffs: coeffs}
+}
+
+// Threshold returns the secret sharing threshold.
+func (p *PriPoly) Threshold() int {
+	return len(p.coeffs)
+}
+
+// Secret returns the shared secret p(0), i.e., the constant term of the
+// polynomial.
+func (p *PriPoly) Secret() kyber.Scalar {
+	return p.coeffs[0]
+}
+
+// Eval computes the private share v = p(i).
+func (p *PriPoly) Eval(i int) *PriShare {
+	xi := p.g.Scalar().SetInt64(1 + int64(i))
+	v := p.g.Scalar().Zero()
+	for j := p.Threshold() - 1; j >= 0; j-- {
+		v.Mul(v, xi)
+		v.Add(v, p.coeffs[j])
+	}
+	return &PriShare{Index(i), v}
+}
+
+// Shares creates a list of n private shares p(1),...,p(n).
+func (p *PriPoly) Shares(n int) []*PriShare {
+	shares := make([]*PriShare, n)
+	for i := range shares {
+		shares[i] = p.Eval(i)
+	}
+	return shares
+}
+
+// Add computes the component-wise sum of the polynomials p and q and returns
+// it as a new polynomial.
+func (p *PriPoly) Add(q *PriPoly) (*PriPoly, error) {
+	if p.g.String() != q.g.String() {
+		return nil, errors.New("share: Go type of group mismatch")
+	}
+	if p.Threshold() != q.Threshold() {
+		return nil, errors.New("share: different number of coefficients")
+	}
+	coeffs := make([]kyber.Scalar, p.Threshold())
+	for i := range coeffs {
+		coeffs[i] = p.g.Scalar().Add(p.coeffs[i], q.coeffs[i])
+	}
+	return &PriPoly{p.g, coeffs}, nil
+}
+
+// Equal checks equality of two secret sharing polynomials p and q.
+func (p *PriPoly) Equal(q *PriPoly) bool {
+	if p.g.String() != q.g.String() {
+		return false
+	}
+	if len(p.coeffs) != len(q.coeffs) {
+		return false
+	}
+	for i := range p.coeffs {
+		if !p.coeffs[i].Equal(q.coeffs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Commit creates a public commitment polynomial for the given base point b or
+// the standard base if b == nil.
+func (p *PriPoly) Commit(b kyber.Point) *PubPoly {
+	commits := make([]kyber.Point, p.Threshold())
+	for i := range commits {
+		commits[i] = p.g.Point().Mul(p.coeffs[i], b)
+	}
+	return &PubPoly{p.g, b, commits}
+}
+
+// Mul multiplies two PriPolys together. The result is a PriPoly of the sum of
+// the two degrees of the original polynomials.
+func (p *PriPoly) Mul(q *PriPoly) *PriPoly {
+	d1 := len(p.coeffs) - 1
+	d2 := len(q.coeffs) - 1
+	newDegree := d1 + d2
+	coeffs := make([]kyber.Scalar, newDegree+1)
+	for i := range coeffs {
+		coeffs[i] = p.g.Scalar().Zero()
+	}
+	for i := range p.coeffs {
+		for j := range q.coeffs {
+			tmp := p.g.Scalar().Mul(p.coeffs[i], q.coeffs[j])
+			coeffs[i+j] = tmp.Add(tmp, coeffs[i+j])
+		}
+	}
+	return &PriPoly{p.g, coeffs}
+}
+
+// Coefficients return the list of coefficients representing p. This
+// information is generally PRIVATE and should not be revealed to a third
+// party other than the dealer.
+func (p *PriPoly) Coefficients() []kyber.Scalar {
+	return p.coeffs
+}
+
+// String returns a human readable representation of the secret sharing
+// polynomial.
+func (p *PriPoly) String() string {
+	var strs = make([]string, len(p.coeffs))
+	for i, c := range p.coeffs {
+		strs[i] = c.String()
+	}
+	return "[ " + strings.Join(strs, ", ") + " ]"
+}
+
+// PubShare represents a public share.
+type PubShare struct {
+	I Index       // Index of the public share
+	V kyber.Point // Value of the public share
+}
+
+// PubPoly represents a public commitment polynomial to a secret sharing
+// polynomial.
+type PubPoly struct {
+	g       kyber.Group   // Cryptographic group
+	b       kyber.Point   // Base point, nil for standard base
+	commits []kyber.Point // Commitments to coefficients of the secret sharing polynomial
+}
+
+// NewPubPoly creates a new public commitment polynomial.
+func NewPubPoly(g kyber.Group, b kyber.Point, commits []kyber.Point) *PubPoly {
+	return &PubPoly{g, b, commits}
+}
+
+// Info returns the base point and the commitments to the polynomial
+// coefficients.
+func (p *PubPoly) Info() (base kyber.Point, commits []kyber.Point) {
+	return p.b, p.commits
+}
+
+// Threshold returns the secret sharing threshold.
+func (p *PubPoly) Threshold() int {
+	return len(p.commits)
+}
+
+// Commit returns the secret commitment p(0), i.e., the constant term of the
+// polynomial.
+func (p *PubPoly) Commit() kyber.Point {
+	return p.commits[0]
+}
+
+// Eval computes the public share v = p(i).
+func (p *PubPoly) Eval(i int) *PubShare {
+	xi := p.g.Scalar().SetInt64(1 + int64(i))
+	v := p.g.Point().Null()
+	for j := p.Threshold() - 1; j >= 0; j-- {
+		v.Mul(xi, v)
+		v.Add(v, p.commits[j])
+	}
+	return &PubShare{Index(i), v}
+}
+
+// Shares creates a list of n public commitment shares p(1),...,p(n).
+func (p *PubPoly) Shares(n int) []*PubShare {
+	shares := make([]*PubShare, n)
+	for i := range shares {
+		shares[i] = p.Eval(i)
+	}
+	return shares
+}
+
+// Add computes the component-wise sum of the polynomials p and q and returns
+// it as a new polynomial. NOTE: If the base points p.b and q.b are different
+// then the base point of the resulting PubPoly cannot be computed without
+// knowing the discrete logarithm between p.b and q.b. In this particular case
+// the resulting PubPoly is invalid.
+func (p *PubPoly) Add(q *PubPoly) (*PubPoly, error) {
+	if p.g.String() != q.g.String() {
+		return nil, errors.New("share: Go type of group mismatch")
+	}
+	if p.Threshold() != q.Threshold() {
+		return nil, errors.New("share: different number of coefficients")
+	}
+	commits := make([]kyber.Point, p.Threshold())
+	for i := range commits {
+		commits[i] = p.g.Point().Add(p.commits[i], q.commits[i])
+	}
+	return &PubPoly{p.g, nil, commits}, nil
+}
+
+// Equal checks equality of two public commitment polynomials p and q.
+func (p *PubPoly) Equal(q *PubPoly) bool {
+	if p.g.String() != q.g.String() {
+		return false
+	}
+	for i := range p.commits {
+		if !p.commits[i].Equal(q.commits[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Check a private share against a public commitment polynomial.
+func (p *PubPoly) Check(s *PriShare) bool {
+	pv := p.Eval(int(s.I))
+	ps := p.g.Point().Mul(s.V, p.b)
+	return pv.V.Equal(ps)
+}
+
+// RecoverSecret reconstructs the shared secret p(0) from a list of private
+// shares using Lagrange interpolation. It stops as soon as t good shares
+// have been found and returns an error if not enough shares were provided.
+func RecoverSecret(g kyber.Group, shares []*PriShare, t, n int) (kyber.Scalar, error) {
+	x := make(map[Index]kyber.Scalar)
+	y := make(map[Index]kyber.Scalar)
+	for _, s := range shares {
+		if s == nil || s.V == nil || s.I >= Index(n) {
+			continue
+		}
+		x[s.I] = g.Scalar().SetInt64(1 + int64(s.I))
+		y[s.I] = s.V
+		if len(x) == t {
+			break
+		}
+	}
+
+	if len(x) < t {
+		return nil, errors.New("share: not enough shares to recover secret")
+	}
+
+	acc := g.Scalar().Zero()
+	num := g.Scalar()
+	den := g.Scalar()
+	tmp := g.Scalar()
+
+	for i, xi := range x {
+		num.Set(y[i])
+		den.One()
+		for j, xj := range x {
+			if i == j {
+				continue
+			}
+			num.Mul(num, xj)
+			den.Mul(den, tmp.Sub(xj, xi))
+		}
+		acc.Add(acc, num.Div(num, den))
+	}
+
+	return acc, nil
+}
+
+// RecoverPriPoly takes a list of shares and the parameters t and n to
+// reconstruct the secret polynomial completely, i.e., all private
+// coefficients. It is up to the caller to make sure that there are enough
+// shares to correctly re-construct the polynomial. There must be at least t
+// shares.
+func RecoverPriPoly(g kyber.Group, shares []*PriShare, t, n int) (*PriPoly, error) {
+	x := make(map[Index]kyber.Scalar)
+	y := make(map[Index]kyber.Scalar)
+	for _, s := range shares {
+		if s == nil || s.V == nil || s.I >= Index(n) {
+			continue
+		}
+		x[s.I] = g.Scalar().SetInt64(1 + int64(s.I))
+		y[s.I] = s.V
+		if len(x) == t {
+			break
+		}
+	}
+
+	if len(x) < t {
+		return nil, errors.New("share: not enough shares to recover private polynomial")
+	}
+
+	var accPoly *PriPoly
+	var err error
+
+	for j := range x {
+		basis := lagrangeBasis(g, j, x)
+		for i := range basis.coeffs {
+			basis.coeffs[i] = basis.coeffs[i].Mul(basis.coeffs[i], y[j])
+		}
+
+		if accPoly == nil {
+			accPoly = basis
+			continue
+		}
+
+		// add all other current results
+		accPoly, err = accPoly.Add(basis)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return accPoly, nil
+}
+
+// RecoverCommit reconstructs the secret commitment p(0) from a list of public
+// shares using Lagrange interpolation. It stops as soon as t good shares
+// have been found and returns an error if not enough shares were provided.
+func RecoverCommit(g kyber.Group, shares []*PubShare, t, n int) (kyber.Point, error) {
+	x := make(map[Index]kyber.Scalar)
+	y := make(map[Index]kyber.Point)
+	for _, s := range shares {
+		if s == nil || s.V == nil || s.I >= Index(n) {
+			continue
+		}
+		x[s.I] = g.Scalar().SetInt64(1 + int64(s.I))
+		y[s.I] = s.V
+		if len(x) == t {
+			break
+		}
+	}
+
+	if len(x) < t {
+		return nil, errors.New("share: not enough good public shares to reconstruct secret commitment")
+	}
+
+	num := g.Scalar()
+	den := g.Scalar()
+	tmp := g.Scalar()
+	Acc := g.Point().Null()
+	Tmp := g.Point()
+
+	for i, xi := range x {
+		num.One()
+		den.One()
+		for j, xj := range x {
+			if i == j {
+				continue
+			}
+			num.Mul(num, xj)
+			den.Mul(den, tmp.Sub(xj, xi))
+		}
+		Tmp.Mul(num.Div(num, den), y[i])
+		Acc.Add(Acc, Tmp)
+	}
+
+	return Acc, nil
+}
+
+// RecoverPubPoly reconstructs the full public commitment polynomial from a
+// list of good public shares via Lagrange interpolation of the basis
+// polynomials, scaled in the exponent by the corresponding share. Unlike
+// RecoverCommit, which only recovers the constant term p(0), this returns
+// every coefficient commitment, so the caller can later evaluate the
+// polynomial at any index without needing all n original shares. It stops
+// as soon as t good shares have been found and returns an error if not
+// enough shares were provided.
+func RecoverPubPoly(g kyber.Group, shares []*PubShare, t, n int) (*PubPoly, error) {
+	x := make(map[Index]kyber.Scalar)
+	y := make(map[Index]kyber.Point)
+	for _, s := range shares {
+		if s == nil || s.V == nil || s.I >= Index(n) {
+			continue
+		}
+		x[s.I] = g.Scalar().SetInt64(1 + int64(s.I))
+		y[s.I] = s.V
+		if len(x) == t {
+			break
+		}
+	}
+
+	if len(x) < t {
+		return nil, errors.New("share: not enough good public shares to reconstruct public polynomial")
+	}
+
+	var accPoly *PubPoly
+	var err error
+
+	for j := range x {
+		basis := lagrangeBasis(g, j, x)
+		commits := make([]kyber.Point, len(basis.coeffs))
+		for i := range commits {
+			commits[i] = g.Point().Mul(basis.coeffs[i], y[j])
+		}
+		pubBasis := &PubPoly{g, nil, commits}
+
+		if accPoly == nil {
+			accPoly = pubBasis
+			continue
+		}
+
+		accPoly, err = accPoly.Add(pubBasis)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return accPoly, nil
+}
+
+// lagrangeBasis returns the Lagrange basis polynomial l_i(x) = prod_{m != i}
+// (x - x_m) / (x_i - x_m) for the given x-coordinates, evaluated as a
+// PriPoly so its coefficients can be reused to scale either private values
+// (RecoverPriPoly) or group elements in the exponent (RecoverPubPoly).
+func lagrangeBasis(g kyber.Group, i Index, xs map[Index]kyber.Scalar) *PriPoly {
+	basis := &PriPoly{g, []kyber.Scalar{g.Scalar().One()}}
+	for m, xm := range xs {
+		if i == m {
+			continue
+		}
+		basis = basis.Mul(minusConst(g, xm))
+		den := g.Scalar().Sub(xs[i], xm)
+		den = den.Inv(den)
+		basis = basis.Mul(&PriPoly{g, []kyber.Scalar{den}})
+	}
+	return basis
+}
+
+// minusConst returns a linear PriPoly representing (x - c).
+func minusConst(g kyber.Group, c kyber.Scalar) *PriPoly {
+	neg := g.Scalar().Neg(c)
+	return &PriPoly{g, []kyber.Scalar{neg, g.Scalar().One()}}
+}
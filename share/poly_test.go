@@ -294,6 +294,32 @@ func TestRecoverPriPoly(test *testing.T) {
 	}
 }
 
+func TestRecoverPubPoly(test *testing.T) {
+	n := 10
+	t := n/2 + 1
+	priPoly := NewPriPoly(suite, t, nil, suite.RandomStream())
+	pubPoly := priPoly.Commit(nil)
+	shares := pubPoly.Shares(n)
+
+	// Pick a random subset of exactly t shares.
+	rnd := make([]byte, n)
+	suite.RandomStream().XORKeyStream(rnd, rnd)
+	subset := make([]*PubShare, n)
+	copy(subset, shares)
+	for i := n - 1; i > 0; i-- {
+		j := int(rnd[i]) % (i + 1)
+		subset[i], subset[j] = subset[j], subset[i]
+	}
+	subset = subset[:t]
+
+	recovered, err := RecoverPubPoly(suite, subset, t, n)
+	assert.Nil(test, err)
+
+	for i := 0; i < n; i++ {
+		assert.True(test, recovered.Eval(i).V.Equal(pubPoly.Eval(i).V))
+	}
+}
+
 func TestPriPolyCoefficients(test *testing.T) {
 	n := 10
 	t := n/2 + 1
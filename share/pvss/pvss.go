@@ -0,0 +1,424 @@
+// Package pvss implements public verifiable secret sharing as introduced in
+// "A Simple Publicly Verifiable Secret Sharing Scheme and its Application to
+// Electronic Voting" by Berry Schoenmakers
+// (https://www.win.tue.nl/~berry/papers/euro99.pdf).
+//
+// Unlike the Feldman/Pedersen verifiable secret sharing implemented in
+// share/vss, PVSS shares can be checked by *any* third party, not just the
+// shareholders, because the dealer never reveals a share in the clear: every
+// share is encrypted under the corresponding shareholder's public key and
+// accompanied by a non-interactive zero-knowledge proof that the encrypted
+// share matches the publicly committed polynomial.
+//
+// PVSS runs in three steps:
+//
+//  1. The dealer calls Escrow() to compute the encrypted shares, their
+//     independent Pedersen commitments, and the accompanying DLEQ proofs,
+//     and distributes one PubVerifiableShare per shareholder.
+//  2. Any third party can check the whole batch with VerifyEncryptedShares(),
+//     which verifies every share's DLEQ proof against its commitment and
+//     then runs a single SCRAPE dual-code check across all the
+//     commitments, catching a dealer that committed to values that don't
+//     lie on one common polynomial without needing n independent checks
+//     of that fact.
+//  3. Once the secret is to be released, each shareholder decrypts its own
+//     share with ShareDecrypt(), which also produces a DLEQ proof that the
+//     decryption is correct. Once t valid decrypted shares are collected,
+//     Recover() reconstructs the shared secret via Lagrange interpolation
+//     in the exponent.
+package pvss
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/dedis/kyber"
+	"github.com/DOSNetwork/core/share"
+	"github.com/DOSNetwork/core/suites"
+)
+
+// Suite describes the functionalities needed by the pvss package.
+type Suite suites.Suite
+
+// DLEQProof is a non-interactive Fiat-Shamir proof that two discrete
+// logarithms, taken with respect to two different bases, are equal:
+// log_G(xG) = log_H(xH).
+type DLEQProof struct {
+	C  kyber.Scalar // challenge
+	R  kyber.Scalar // response
+	VG kyber.Point  // commitment with respect to base point G
+	VH kyber.Point  // commitment with respect to base point H
+}
+
+// newDLEQProof creates a NIZK dlog-equality proof for the scalar x with
+// respect to the base points G and H. It returns the proof together with
+// the two public values xG and xH.
+func newDLEQProof(suite Suite, G, H kyber.Point, x kyber.Scalar) (proof *DLEQProof, xG, xH kyber.Point, err error) {
+	xG = suite.Point().Mul(x, G)
+	xH = suite.Point().Mul(x, H)
+
+	v := suite.Scalar().Pick(suite.RandomStream())
+	vG := suite.Point().Mul(v, G)
+	vH := suite.Point().Mul(v, H)
+
+	c, err := hashPoints(suite, xG, xH, vG, vH)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	r := suite.Scalar().Sub(v, suite.Scalar().Mul(c, x))
+
+	return &DLEQProof{C: c, R: r, VG: vG, VH: vH}, xG, xH, nil
+}
+
+// Verify checks that the proof p attests that log_G(xG) = log_H(xH).
+func (p *DLEQProof) Verify(suite Suite, G, H, xG, xH kyber.Point) error {
+	rG := suite.Point().Mul(p.R, G)
+	rH := suite.Point().Mul(p.R, H)
+	cxG := suite.Point().Add(rG, suite.Point().Mul(p.C, xG))
+	cxH := suite.Point().Add(rH, suite.Point().Mul(p.C, xH))
+
+	c, err := hashPoints(suite, xG, xH, cxG, cxH)
+	if err != nil {
+		return err
+	}
+
+	if !(p.VG.Equal(cxG) && p.VH.Equal(cxH) && c.Equal(p.C)) {
+		return errors.New("pvss: invalid DLEQ proof")
+	}
+	return nil
+}
+
+// MarshalBinary encodes p into a wire-friendly, length-prefixed byte
+// string, so it can be attached to a broadcast message alongside the rest
+// of a PubVerifiableShare. suite must be the same suite the proof was
+// created with, since scalars and points don't carry their group along.
+func (p *DLEQProof) MarshalBinary(suite Suite) ([]byte, error) {
+	buf := make([]byte, 0, 128)
+	var err error
+	if buf, err = appendScalar(buf, p.C); err != nil {
+		return nil, err
+	}
+	if buf, err = appendScalar(buf, p.R); err != nil {
+		return nil, err
+	}
+	if buf, err = appendPoint(buf, p.VG); err != nil {
+		return nil, err
+	}
+	if buf, err = appendPoint(buf, p.VH); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes p from the format produced by MarshalBinary,
+// using suite to reconstruct the scalars and points.
+func (p *DLEQProof) UnmarshalBinary(suite Suite, data []byte) error {
+	c, data, err := readScalar(suite, data)
+	if err != nil {
+		return err
+	}
+	r, data, err := readScalar(suite, data)
+	if err != nil {
+		return err
+	}
+	vg, data, err := readPoint(suite, data)
+	if err != nil {
+		return err
+	}
+	vh, _, err := readPoint(suite, data)
+	if err != nil {
+		return err
+	}
+	p.C, p.R, p.VG, p.VH = c, r, vg, vh
+	return nil
+}
+
+// appendPoint appends p's marshalled form to buf, preceded by its length.
+func appendPoint(buf []byte, p kyber.Point) ([]byte, error) {
+	b, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(b)))
+	return append(buf, b...), nil
+}
+
+// appendScalar appends s's marshalled form to buf, preceded by its length.
+func appendScalar(buf []byte, s kyber.Scalar) ([]byte, error) {
+	b, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(b)))
+	return append(buf, b...), nil
+}
+
+// readPoint reads a length-prefixed point off the front of data, returning
+// the decoded point and the remaining bytes.
+func readPoint(suite Suite, data []byte) (kyber.Point, []byte, error) {
+	b, rest, err := readLenPrefixed(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := suite.Point()
+	if err := p.UnmarshalBinary(b); err != nil {
+		return nil, nil, err
+	}
+	return p, rest, nil
+}
+
+// readScalar reads a length-prefixed scalar off the front of data,
+// returning the decoded scalar and the remaining bytes.
+func readScalar(suite Suite, data []byte) (kyber.Scalar, []byte, error) {
+	b, rest, err := readLenPrefixed(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	s := suite.Scalar()
+	if err := s.UnmarshalBinary(b); err != nil {
+		return nil, nil, err
+	}
+	return s, rest, nil
+}
+
+// readLenPrefixed reads a uint32-length-prefixed byte string off the
+// front of data, returning it and the remaining bytes.
+func readLenPrefixed(data []byte) ([]byte, []byte, error) {
+	if len(data) < 4 {
+		return nil, nil, errors.New("pvss: truncated message")
+	}
+	n := binary.BigEndian.Uint32(data)
+	data = data[4:]
+	if uint32(len(data)) < n {
+		return nil, nil, errors.New("pvss: truncated message")
+	}
+	return data[:n], data[n:], nil
+}
+
+// hashPoints derives a Fiat-Shamir challenge scalar from the marshalled
+// representation of the given points.
+func hashPoints(suite Suite, points ...kyber.Point) (kyber.Scalar, error) {
+	h := suite.Hash()
+	for _, p := range points {
+		if _, err := p.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return suite.Scalar().SetBytes(h.Sum(nil)), nil
+}
+
+// PubVerifiableShare is a publicly verifiable share: the encrypted share
+// value handed to a single shareholder, together with the DLEQ proof
+// demonstrating that it corresponds to the Pedersen commitment of the
+// dealer's polynomial at the same index.
+type PubVerifiableShare struct {
+	S share.PubShare // encrypted share Y_i = pub_i^p(i)
+	P DLEQProof      // proof that log_h(C_i) == log_pub_i(Y_i)
+}
+
+// MarshalBinary encodes s into a wire-friendly, length-prefixed byte
+// string suitable for broadcast. suite must match the suite s was
+// produced under.
+func (s *PubVerifiableShare) MarshalBinary(suite Suite) ([]byte, error) {
+	buf := binary.BigEndian.AppendUint32(nil, uint32(s.S.I))
+	buf, err := appendPoint(buf, s.S.V)
+	if err != nil {
+		return nil, err
+	}
+	proofBytes, err := s.P.MarshalBinary(suite)
+	if err != nil {
+		return nil, err
+	}
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(proofBytes)))
+	return append(buf, proofBytes...), nil
+}
+
+// UnmarshalBinary decodes s from the format produced by MarshalBinary,
+// using suite to reconstruct the scalars and points.
+func (s *PubVerifiableShare) UnmarshalBinary(suite Suite, data []byte) error {
+	if len(data) < 4 {
+		return errors.New("pvss: truncated message")
+	}
+	idx := binary.BigEndian.Uint32(data)
+	data = data[4:]
+
+	v, data, err := readPoint(suite, data)
+	if err != nil {
+		return err
+	}
+
+	proofBytes, _, err := readLenPrefixed(data)
+	if err != nil {
+		return err
+	}
+	var proof DLEQProof
+	if err := proof.UnmarshalBinary(suite, proofBytes); err != nil {
+		return err
+	}
+
+	s.S = share.PubShare{I: share.Index(idx), V: v}
+	s.P = proof
+	return nil
+}
+
+// Escrow creates a fresh, random secret of degree t-1 and distributes it
+// publicly verifiably among the holders of the public keys in pubs. It
+// returns the independent Pedersen commitment C_i = h^p(i) for every
+// shareholder (so that VerifyEncryptedShares can run the SCRAPE dual-code
+// check against them instead of trusting a single published polynomial)
+// and one PubVerifiableShare per entry of pubs, wire-ready for broadcast
+// to everyone, not just the shareholders.
+func Escrow(suite Suite, h kyber.Point, pubs []kyber.Point, t int) (commits []kyber.Point, shares []*PubVerifiableShare, err error) {
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	return escrowSecret(suite, h, pubs, t, secret)
+}
+
+// escrowSecret runs Escrow for an already chosen secret; split out so tests
+// can check recovery against a known value.
+func escrowSecret(suite Suite, h kyber.Point, pubs []kyber.Point, t int, secret kyber.Scalar) ([]kyber.Point, []*PubVerifiableShare, error) {
+	priPoly := share.NewPriPoly(suite, t, secret, suite.RandomStream())
+	priShares := priPoly.Shares(len(pubs))
+	commitPoly := priPoly.Commit(h)
+
+	commits := make([]kyber.Point, len(pubs))
+	shares := make([]*PubVerifiableShare, len(pubs))
+	for i, ps := range priShares {
+		commits[i] = commitPoly.Eval(i).V
+		proof, _, Y, err := newDLEQProof(suite, h, pubs[i], ps.V)
+		if err != nil {
+			return nil, nil, err
+		}
+		shares[i] = &PubVerifiableShare{
+			S: share.PubShare{I: ps.I, V: Y},
+			P: *proof,
+		}
+	}
+	return commits, shares, nil
+}
+
+// VerifyEncryptedShares checks every encrypted share in the batch against
+// the dealer's independently published per-share commitments commits
+// (commits[i] = h^p(i), as returned by Escrow): for each i it verifies the
+// DLEQ proof attesting log_h(C_i) == log_pub_i(Y_i), i.e. that the share
+// was honestly encrypted under pubs[i] for the same polynomial value
+// commits[i] commits to, and then runs the SCRAPE dual-code check (see
+// scrapeCodeword) against commits as a single batched test that every C_i
+// lies on one common degree t-1 polynomial, catching a dealer that
+// committed to inconsistent values without requiring the verifier to
+// trust a fully published t-coefficient polynomial.
+func VerifyEncryptedShares(suite Suite, h kyber.Point, pubs []kyber.Point, t int, commits []kyber.Point, shares []*PubVerifiableShare) error {
+	n := len(pubs)
+	if len(shares) != n || len(commits) != n {
+		return errors.New("pvss: number of shares or commits does not match number of public keys")
+	}
+
+	for i, s := range shares {
+		if s.S.I != share.Index(i) {
+			return errors.New("pvss: share index mismatch")
+		}
+		if err := s.P.Verify(suite, h, pubs[i], commits[i], s.S.V); err != nil {
+			return err
+		}
+	}
+
+	codeword := scrapeCodeword(suite, n, t)
+	acc := suite.Point().Null()
+	for i, Ci := range commits {
+		acc = suite.Point().Add(acc, suite.Point().Mul(codeword[i], Ci))
+	}
+	if !acc.Equal(suite.Point().Null()) {
+		return errors.New("pvss: SCRAPE dual-code check failed")
+	}
+
+	return nil
+}
+
+// scrapeCodeword draws a fresh random codeword m_0..m_{n-1} that is
+// orthogonal to every vector of evaluations at 0..n-1 of a degree-(t-1)
+// polynomial: the dual of an [n, t] Reed-Solomon code evaluated at
+// 0..n-1 is itself an [n, n-t] Reed-Solomon code scaled by the
+// barycentric weights w_i = 1 / prod_{k != i} (i - k), i.e. m_i = r(i) *
+// w_i for an arbitrary polynomial r of degree <= n-t-1. Any such m
+// therefore satisfies Sum_i m_i * p(i) = 0 for every polynomial p of
+// degree < t, so Sum_i m_i * C_i collapses to the identity iff the
+// committed values C_0..C_{n-1} all lie on one common degree-(t-1)
+// polynomial, which is exactly the batch consistency check SCRAPE uses in
+// place of n independent openings. The verifier draws its own m, so
+// unlike the DLEQ proofs nothing here needs to be a Fiat-Shamir NIZK.
+func scrapeCodeword(suite Suite, n, t int) []kyber.Scalar {
+	rCoeffs := make([]kyber.Scalar, n-t)
+	for i := range rCoeffs {
+		rCoeffs[i] = suite.Scalar().Pick(suite.RandomStream())
+	}
+
+	codeword := make([]kyber.Scalar, n)
+	for i := 0; i < n; i++ {
+		codeword[i] = evalPoly(suite, rCoeffs, i)
+		codeword[i] = suite.Scalar().Mul(codeword[i], barycentricWeight(suite, i, n))
+	}
+	return codeword
+}
+
+// evalPoly evaluates, via Horner's method, the polynomial with the given
+// coefficients (lowest degree first) at x.
+func evalPoly(suite Suite, coeffs []kyber.Scalar, x int) kyber.Scalar {
+	result := suite.Scalar().Zero()
+	xs := suite.Scalar().SetInt64(int64(x))
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		result = suite.Scalar().Add(suite.Scalar().Mul(result, xs), coeffs[i])
+	}
+	return result
+}
+
+// barycentricWeight returns w_i = 1 / prod_{k != i} (i - k) for the nodes
+// 0..n-1, the weight that turns evaluations of an arbitrary polynomial at
+// those nodes into a codeword orthogonal to every lower-degree one.
+func barycentricWeight(suite Suite, i, n int) kyber.Scalar {
+	w := suite.Scalar().One()
+	for k := 0; k < n; k++ {
+		if k == i {
+			continue
+		}
+		w = suite.Scalar().Mul(w, suite.Scalar().SetInt64(int64(i-k)))
+	}
+	return suite.Scalar().Inv(w)
+}
+
+// ShareDecrypt decrypts the PubVerifiableShare Y_i held by the owner of
+// priv (whose public key is pub = g^priv) into the clear share
+// S_i = Y_i^{1/priv}, along with a DLEQ proof that log_g(pub) == log_{S_i}(Y_i),
+// which lets any third party check the decryption was done honestly.
+func ShareDecrypt(suite Suite, priv kyber.Scalar, encShare *PubVerifiableShare) (*PubVerifiableShare, error) {
+	invPriv := suite.Scalar().Inv(priv)
+	S := suite.Point().Mul(invPriv, encShare.S.V)
+
+	proof, _, _, err := newDLEQProof(suite, suite.Point().Base(), S, priv)
+	if err != nil {
+		return nil, err
+	}
+	return &PubVerifiableShare{
+		S: share.PubShare{I: encShare.S.I, V: S},
+		P: *proof,
+	}, nil
+}
+
+// VerifyDecryptedShare checks that the decrypted share ds was honestly
+// derived from its encrypted counterpart encShare under the public key pub.
+func VerifyDecryptedShare(suite Suite, pub kyber.Point, encShare, ds *PubVerifiableShare) error {
+	return ds.P.Verify(suite, suite.Point().Base(), ds.S.V, pub, encShare.S.V)
+}
+
+// Recover reconstructs the shared secret from t (or more) decrypted shares
+// via Lagrange interpolation in the exponent.
+func Recover(suite Suite, shares []*PubVerifiableShare, t int) (kyber.Point, error) {
+	n := 0
+	pubShares := make([]*share.PubShare, len(shares))
+	for i, s := range shares {
+		pubShares[i] = &s.S
+		if int(s.S.I)+1 > n {
+			n = int(s.S.I) + 1
+		}
+	}
+	return share.RecoverCommit(suite, pubShares, t, n)
+}
@@ -0,0 +1,145 @@
+package pvss
+
+import (
+	"testing"
+
+	"github.com/DOSNetwork/core/share"
+	"github.com/DOSNetwork/core/suites"
+	"github.com/dedis/kyber"
+)
+
+var suite = suites.MustFind("bn256")
+
+func genPubs(n int) ([]kyber.Scalar, []kyber.Point) {
+	privs := make([]kyber.Scalar, n)
+	pubs := make([]kyber.Point, n)
+	for i := 0; i < n; i++ {
+		privs[i] = suite.Scalar().Pick(suite.RandomStream())
+		pubs[i] = suite.Point().Mul(privs[i], nil)
+	}
+	return privs, pubs
+}
+
+func TestPVSSEscrowVerifyDecryptRecover(test *testing.T) {
+	n := 10
+	t := n/2 + 1
+	h := suite.Point().Pick(suite.RandomStream())
+	secret := suite.Scalar().Pick(suite.RandomStream())
+
+	privs, pubs := genPubs(n)
+
+	commit, encShares, err := escrowSecret(suite, h, pubs, t, secret)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if err := VerifyEncryptedShares(suite, h, pubs, t, commit, encShares); err != nil {
+		test.Fatal(err)
+	}
+
+	decShares := make([]*PubVerifiableShare, n)
+	for i := range decShares {
+		ds, err := ShareDecrypt(suite, privs[i], encShares[i])
+		if err != nil {
+			test.Fatal(err)
+		}
+		if err := VerifyDecryptedShare(suite, pubs[i], encShares[i], ds); err != nil {
+			test.Fatalf("decrypted share %d failed verification: %v", i, err)
+		}
+		decShares[i] = ds
+	}
+
+	// Recover from the last t of n shares, not the first t, so that a
+	// regression which assumes the subset is contiguous from index 0 (as
+	// decShares[:t] would hide) gets caught.
+	recovered, err := Recover(suite, decShares[n-t:], t)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	// Recover() reconstructs g^secret from the decrypted shares, which live
+	// on the encryption base g, not h (the commitment base used above for
+	// the dealer's Pedersen commitment commit).
+	if !recovered.Equal(suite.Point().Mul(secret, nil)) {
+		test.Fatal("recovered secret does not match the escrowed secret")
+	}
+}
+
+func TestPVSSVerifyEncryptedSharesDetectsTampering(test *testing.T) {
+	n := 10
+	t := n/2 + 1
+	h := suite.Point().Pick(suite.RandomStream())
+
+	_, pubs := genPubs(n)
+
+	commit, encShares, err := Escrow(suite, h, pubs, t)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	// Tamper with one encrypted share.
+	encShares[0].S.V = suite.Point().Pick(suite.RandomStream())
+
+	if err := VerifyEncryptedShares(suite, h, pubs, t, commit, encShares); err == nil {
+		test.Fatal("expected tampered share to fail verification")
+	}
+}
+
+func TestPVSSVerifyEncryptedSharesDetectsInconsistentCommit(test *testing.T) {
+	n := 10
+	t := n/2 + 1
+	h := suite.Point().Pick(suite.RandomStream())
+
+	_, pubs := genPubs(n)
+
+	commits, encShares, err := Escrow(suite, h, pubs, t)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	// Swap in a self-consistent but off-polynomial commitment/share pair
+	// for shareholder 0: the DLEQ proof alone can't catch this, since it
+	// only attests that Y_0 and C_0 agree with each other, not that C_0
+	// lies on the same polynomial as every other C_i. Only the SCRAPE
+	// dual-code check, run across the whole batch, can.
+	x := suite.Scalar().Pick(suite.RandomStream())
+	proof, _, y, err := newDLEQProof(suite, h, pubs[0], x)
+	if err != nil {
+		test.Fatal(err)
+	}
+	commits[0] = suite.Point().Mul(x, h)
+	encShares[0] = &PubVerifiableShare{S: share.PubShare{I: 0, V: y}, P: *proof}
+
+	if err := VerifyEncryptedShares(suite, h, pubs, t, commits, encShares); err == nil {
+		test.Fatal("expected SCRAPE dual-code check to catch the inconsistent commitment")
+	}
+}
+
+func TestPubVerifiableShareMarshalBinaryRoundTrip(test *testing.T) {
+	n := 3
+	t := 2
+	h := suite.Point().Pick(suite.RandomStream())
+	_, pubs := genPubs(n)
+
+	commits, encShares, err := Escrow(suite, h, pubs, t)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	data, err := encShares[0].MarshalBinary(suite)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	var decoded PubVerifiableShare
+	if err := decoded.UnmarshalBinary(suite, data); err != nil {
+		test.Fatal(err)
+	}
+
+	if decoded.S.I != encShares[0].S.I || !decoded.S.V.Equal(encShares[0].S.V) {
+		test.Fatal("unmarshalled share does not match the original")
+	}
+	if err := decoded.P.Verify(suite, h, pubs[0], commits[0], decoded.S.V); err != nil {
+		test.Fatalf("unmarshalled proof failed verification: %v", err)
+	}
+}